@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxAgentTurns bounds how many tool-call round trips a single
+// suggestion request may take, so a model that keeps calling tools
+// can't loop forever.
+const maxAgentTurns = 6
+
+// suggestWithTools runs the Ollama chat agent loop: it advertises
+// agentTools to the model and, as long as the model keeps responding
+// with tool_calls instead of a final message, executes those tools
+// locally and feeds the results back as `role: "tool"` messages. This
+// lets the model inspect prior commit style and surrounding code before
+// settling on a suggestion.
+func (p *ollamaProvider) suggestWithTools(ctx context.Context, diff, stat string, n int, temperature float64) ([]commitSuggestion, error) {
+	messages := []chatMessage{
+		{Role: "system", Content: "You write concise git commit messages. You may call tools to inspect the repository before answering."},
+		{Role: "user", Content: buildPrompt(diff, stat)},
+	}
+
+	format := suggestionsFormatSchema(n)
+
+	for turn := 0; turn < maxAgentTurns; turn++ {
+		reqBody := ollamaChatRequest{
+			Model:    p.model,
+			Messages: messages,
+			Stream:   false,
+			Tools:    agentTools,
+			Options:  map[string]any{"temperature": temperature},
+		}
+		// Only attach the structured suggestions schema on the last
+		// allowed turn; Ollama can refuse to emit tool_calls at all once
+		// a `format` schema is attached. A final answer given on an
+		// earlier turn is parsed without the schema.
+		if turn == maxAgentTurns-1 {
+			reqBody.Format = format
+		}
+
+		out, err := p.chat(ctx, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(out.Message.ToolCalls) == 0 {
+			msgs, err := parseSuggestions(out.Message.Content)
+			if err != nil {
+				return nil, err
+			}
+			if len(msgs) > n {
+				msgs = msgs[:n]
+			}
+			return msgs, nil
+		}
+
+		messages = append(messages, out.Message)
+		for _, call := range out.Message.ToolCalls {
+			result, err := executeTool(call.Function.Name, call.Function.Arguments)
+			if err != nil {
+				result = "error: " + err.Error()
+			}
+			messages = append(messages, chatMessage{
+				Role:     "tool",
+				Content:  result,
+				ToolName: call.Function.Name,
+			})
+		}
+	}
+
+	return nil, fmt.Errorf("agent loop exceeded %d turns without a final answer", maxAgentTurns)
+}
+
+// Summarize asks the model to answer a single free-form prompt, with no
+// tools and no structured-output schema attached.
+func (p *ollamaProvider) Summarize(ctx context.Context, prompt string) (string, error) {
+	out, err := p.chat(ctx, ollamaChatRequest{
+		Model:    p.model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+		Stream:   false,
+	})
+	if err != nil {
+		return "", err
+	}
+	return out.Message.Content, nil
+}
+
+// chat sends a single non-streaming chat request and decodes the
+// response, shared by the tool-enabled agent loop and Summarize.
+func (p *ollamaProvider) chat(ctx context.Context, reqBody ollamaChatRequest) (*ollamaChatResponse, error) {
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(p.endpoint, "/") + "/api/chat"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: p.timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("ollama request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama error %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var out ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &out, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// commitSuggestion is the structured form a provider returns for each
+// candidate commit: enough to render a full Conventional Commits
+// message (type(scope): subject, wrapped body, BREAKING CHANGE and
+// other footers) rather than a single flat line.
+type commitSuggestion struct {
+	Type     string   `json:"type"`
+	Scope    string   `json:"scope,omitempty"`
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body,omitempty"`
+	Breaking bool     `json:"breaking,omitempty"`
+	Footers  []string `json:"footers,omitempty"`
+}
+
+// suggestionsPayload is the structured-output shape we ask models for:
+// {"suggestions": [{"type": "...", "subject": "...", ...}, ...]}.
+type suggestionsPayload struct {
+	Suggestions []commitSuggestion `json:"suggestions"`
+}
+
+// defaultCommitTypes is the Conventional Commits allow-list used when
+// the user hasn't overridden it with -types.
+var defaultCommitTypes = []string{"feat", "fix", "docs", "refactor", "test", "chore", "perf", "build", "ci"}
+
+const maxSubjectLen = 72
+
+// parseCommitTypes splits a comma-separated -types flag value into an
+// allow-list, trimming whitespace and dropping empty entries.
+func parseCommitTypes(s string) []string {
+	var types []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return defaultCommitTypes
+	}
+	return types
+}
+
+func isAllowedType(t string, allowed []string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(t, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// deriveScope falls back to the shared top-level directory of the
+// staged files when the model didn't supply one. Files spread across
+// more than one top-level directory have no single natural scope, so it
+// returns "" rather than guessing.
+func deriveScope(stagedFiles []string) string {
+	var top string
+	for _, f := range stagedFiles {
+		dir := strings.SplitN(path.Clean(f), "/", 2)[0]
+		if dir == "." || dir == f {
+			return ""
+		}
+		if top == "" {
+			top = dir
+		} else if top != dir {
+			return ""
+		}
+	}
+	return top
+}
+
+// normalizeCommitSuggestion validates and fills in a suggestion against
+// the repo's conventions: a type must be on the allow-list, a missing
+// scope is derived from the staged files, and an over-long subject is
+// truncated with a warning rather than silently sent to git.
+func normalizeCommitSuggestion(cs commitSuggestion, allowed []string, stagedFiles []string) commitSuggestion {
+	cs.Subject = strings.TrimSpace(cs.Subject)
+	cs.Type = strings.ToLower(strings.TrimSpace(cs.Type))
+	if cs.Type == "" || !isAllowedType(cs.Type, allowed) {
+		cs.Type = "chore"
+	}
+	if cs.Scope == "" {
+		cs.Scope = deriveScope(stagedFiles)
+	}
+
+	header := commitHeader(cs)
+	if len(header) > maxSubjectLen {
+		overflow := len(header) - maxSubjectLen
+		truncated := false
+		if overflow < len(cs.Subject) {
+			cs.Subject = strings.TrimSpace(cs.Subject[:len(cs.Subject)-overflow])
+			truncated = true
+		} else if cs.Scope != "" {
+			// The "type(scope): " prefix alone already exceeds the
+			// limit; drop the scope so there's still a subject left to
+			// trim against.
+			cs.Scope = ""
+			header = commitHeader(cs)
+			if overflow = len(header) - maxSubjectLen; overflow > 0 && overflow < len(cs.Subject) {
+				cs.Subject = strings.TrimSpace(cs.Subject[:len(cs.Subject)-overflow])
+				truncated = true
+			}
+		}
+		if truncated {
+			fmt.Fprintf(os.Stderr, "warning: truncated subject to fit %d chars: %q\n", maxSubjectLen, commitHeader(cs))
+		}
+	}
+	return cs
+}
+
+// commitHeader renders the "type(scope): subject" header line.
+func commitHeader(cs commitSuggestion) string {
+	if cs.Scope != "" {
+		return fmt.Sprintf("%s(%s): %s", cs.Type, cs.Scope, cs.Subject)
+	}
+	return fmt.Sprintf("%s: %s", cs.Type, cs.Subject)
+}
+
+// renderCommitMessage produces the full commit message text: header,
+// wrapped body, and footers (BREAKING CHANGE first, if any).
+func renderCommitMessage(cs commitSuggestion) string {
+	var b strings.Builder
+	b.WriteString(commitHeader(cs))
+
+	if body := strings.TrimSpace(cs.Body); body != "" {
+		b.WriteString("\n\n")
+		b.WriteString(wrapText(body, 72))
+	}
+
+	var footers []string
+	if cs.Breaking {
+		reason := "see body for details"
+		if body := strings.TrimSpace(cs.Body); body != "" {
+			reason = body
+		}
+		footers = append(footers, "BREAKING CHANGE: "+reason)
+	}
+	footers = append(footers, cs.Footers...)
+	if len(footers) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(strings.Join(footers, "\n"))
+	}
+
+	return b.String()
+}
+
+// wrapText greedily wraps s to the given column width, preserving
+// existing blank-line paragraph breaks.
+func wrapText(s string, width int) string {
+	paragraphs := strings.Split(s, "\n\n")
+	for i, para := range paragraphs {
+		paragraphs[i] = wrapParagraph(para, width)
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func wrapParagraph(para string, width int) string {
+	words := strings.Fields(para)
+	if len(words) == 0 {
+		return para
+	}
+	var lines []string
+	line := words[0]
+	for _, w := range words[1:] {
+		if len(line)+1+len(w) > width {
+			lines = append(lines, line)
+			line = w
+			continue
+		}
+		line += " " + w
+	}
+	lines = append(lines, line)
+	return strings.Join(lines, "\n")
+}
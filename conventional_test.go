@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeriveScope(t *testing.T) {
+	if got := deriveScope([]string{"provider.go", "provider_ollama.go"}); got != "" {
+		t.Fatalf("expected no scope for top-level files, got %q", got)
+	}
+	if got := deriveScope([]string{"internal/foo.go", "internal/bar.go"}); got != "internal" {
+		t.Fatalf("expected scope %q, got %q", "internal", got)
+	}
+	if got := deriveScope([]string{"internal/foo.go", "cmd/bar.go"}); got != "" {
+		t.Fatalf("expected no scope across directories, got %q", got)
+	}
+}
+
+func TestNormalizeCommitSuggestionTruncatesLongSubject(t *testing.T) {
+	cs := commitSuggestion{Type: "feat", Scope: "provider", Subject: strings.Repeat("x", 100)}
+	got := normalizeCommitSuggestion(cs, defaultCommitTypes, nil)
+	if header := commitHeader(got); len(header) > maxSubjectLen {
+		t.Fatalf("expected header to fit %d chars, got %d: %q", maxSubjectLen, len(header), header)
+	}
+}
+
+func TestNormalizeCommitSuggestionDropsScopeWhenPrefixTooLong(t *testing.T) {
+	cs := commitSuggestion{Type: "feat", Scope: strings.Repeat("s", maxSubjectLen), Subject: "add x"}
+	got := normalizeCommitSuggestion(cs, defaultCommitTypes, nil)
+	if got.Scope != "" {
+		t.Fatalf("expected scope to be dropped, got %q", got.Scope)
+	}
+	if header := commitHeader(got); len(header) > maxSubjectLen {
+		t.Fatalf("expected header to fit %d chars, got %d: %q", maxSubjectLen, len(header), header)
+	}
+}
+
+func TestRenderCommitMessage(t *testing.T) {
+	cs := commitSuggestion{Type: "fix", Scope: "provider", Subject: "handle timeouts", Breaking: true, Body: "explains the change"}
+	got := renderCommitMessage(cs)
+	want := "fix(provider): handle timeouts\n\nexplains the change\n\nBREAKING CHANGE: explains the change"
+	if got != want {
+		t.Fatalf("unexpected message:\n%s", got)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// defaultIgnoreGlobs are dropped from the diff before it ever reaches a
+// token budget check, on top of whatever the repo's own .gocommitignore
+// adds: lockfiles and vendored/generated trees are rarely worth an LLM's
+// attention and burn the context window fast.
+var defaultIgnoreGlobs = []string{
+	"*.lock",
+	"go.sum",
+	"package-lock.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Cargo.lock",
+	"vendor/**",
+	"node_modules/**",
+	"dist/**",
+	"build/**",
+}
+
+// hunkTokenBudget is the per-hunk threshold above which a hunk is
+// summarized instead of sent verbatim, once the whole diff is over
+// maxTokens.
+const hunkTokenBudget = 300
+
+var fileHeaderRe = regexp.MustCompile(`(?m)^diff --git a/(\S+) b/(\S+)`)
+var hunkHeaderRe = regexp.MustCompile(`(?m)^@@ .* @@.*$`)
+
+// diffFile is one file's section of a unified diff, split into its
+// individual hunks so each can be token-counted and, if needed,
+// summarized independently.
+type diffFile struct {
+	path   string
+	header string // everything before the first hunk (---/+++ lines etc.)
+	hunks  []string
+}
+
+// estimateTokens is a cheap stand-in for a real tokenizer: good enough
+// to decide whether a diff is in the right ballpark for a model's
+// context window.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// loadGocommitIgnore reads glob patterns from a .gocommitignore file at
+// the repo root, one per line, ignoring blanks and #-comments. A
+// missing file yields no extra patterns.
+func loadGocommitIgnore(root string) []string {
+	b, err := os.ReadFile(filepath.Join(root, ".gocommitignore"))
+	if err != nil {
+		return nil
+	}
+	var patterns []string
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// matchesIgnoreGlob reports whether path matches pattern, supporting a
+// "dir/**" suffix for whole-subtree matches in addition to plain
+// filepath.Match globs (evaluated against both the full path and its
+// base name, so "*.lock" matches "sub/dir/yarn.lock" too).
+func matchesIgnoreGlob(pattern, path string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return path == dir || strings.HasPrefix(path, dir+"/")
+	}
+	if ok, _ := filepath.Match(pattern, path); ok {
+		return true
+	}
+	ok, _ := filepath.Match(pattern, filepath.Base(path))
+	return ok
+}
+
+// splitDiffByFile breaks a unified diff into per-file sections, and
+// each section into its hunks, so later stages can filter or summarize
+// at file or hunk granularity.
+func splitDiffByFile(diff string) []diffFile {
+	headerIdx := fileHeaderRe.FindAllStringSubmatchIndex(diff, -1)
+	if len(headerIdx) == 0 {
+		return nil
+	}
+
+	var files []diffFile
+	for i, idx := range headerIdx {
+		start := idx[0]
+		end := len(diff)
+		if i+1 < len(headerIdx) {
+			end = headerIdx[i+1][0]
+		}
+		path := diff[idx[2]:idx[3]]
+		files = append(files, splitFileSection(path, diff[start:end]))
+	}
+	return files
+}
+
+// splitFileSection separates a file's leading header (the "diff --git"/
+// "---"/"+++" lines) from its @@ hunks.
+func splitFileSection(path, section string) diffFile {
+	locs := hunkHeaderRe.FindAllStringIndex(section, -1)
+	if len(locs) == 0 {
+		return diffFile{path: path, header: section}
+	}
+
+	df := diffFile{path: path, header: section[:locs[0][0]]}
+	for i, loc := range locs {
+		start := loc[0]
+		end := len(section)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		df.hunks = append(df.hunks, section[start:end])
+	}
+	return df
+}
+
+func (f diffFile) text() string {
+	return f.header + strings.Join(f.hunks, "")
+}
+
+// filterIgnoredFiles drops whole files matching any ignore glob.
+func filterIgnoredFiles(files []diffFile, patterns []string) []diffFile {
+	var out []diffFile
+	for _, f := range files {
+		ignored := false
+		for _, pat := range patterns {
+			if matchesIgnoreGlob(pat, f.path) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// budgetDiff applies the full prompt-size-management pipeline: it drops
+// ignored files, and if what's left is still over maxTokens, summarizes
+// every oversized hunk through the provider (map) and rebuilds a diff
+// text from the summaries plus the remaining verbatim hunks (reduce).
+// A maxTokens of 0 disables the token budget entirely.
+func budgetDiff(ctx context.Context, p Provider, diff string, root string, maxTokens int) (string, error) {
+	files := splitDiffByFile(diff)
+	if files == nil {
+		return diff, nil
+	}
+
+	patterns := append(append([]string{}, defaultIgnoreGlobs...), loadGocommitIgnore(root)...)
+	files = filterIgnoredFiles(files, patterns)
+
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString(f.text())
+	}
+	filtered := b.String()
+
+	if maxTokens <= 0 || estimateTokens(filtered) <= maxTokens {
+		return filtered, nil
+	}
+
+	for i, f := range files {
+		for j, hunk := range f.hunks {
+			if estimateTokens(hunk) <= hunkTokenBudget {
+				continue
+			}
+			summary, err := p.Summarize(ctx, "Summarize this diff hunk in one sentence:\n\n"+hunk)
+			if err != nil {
+				return "", fmt.Errorf("summarize hunk in %s: %w", f.path, err)
+			}
+			files[i].hunks[j] = "# " + strings.TrimSpace(summary) + "\n"
+		}
+	}
+
+	var out strings.Builder
+	for _, f := range files {
+		out.WriteString(f.header)
+		for _, hunk := range f.hunks {
+			out.WriteString(hunk)
+		}
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+const sampleDiff = `diff --git a/foo.go b/foo.go
+index 111..222 100644
+--- a/foo.go
++++ b/foo.go
+@@ -1,2 +1,2 @@
+-old
++new
+diff --git a/yarn.lock b/yarn.lock
+index 333..444 100644
+--- a/yarn.lock
++++ b/yarn.lock
+@@ -1,1 +1,1 @@
+-a
++b
+`
+
+func TestSplitDiffByFile(t *testing.T) {
+	files := splitDiffByFile(sampleDiff)
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if files[0].path != "foo.go" || files[1].path != "yarn.lock" {
+		t.Fatalf("unexpected paths: %q %q", files[0].path, files[1].path)
+	}
+	if len(files[0].hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(files[0].hunks))
+	}
+}
+
+func TestFilterIgnoredFiles(t *testing.T) {
+	files := splitDiffByFile(sampleDiff)
+	filtered := filterIgnoredFiles(files, defaultIgnoreGlobs)
+	if len(filtered) != 1 || filtered[0].path != "foo.go" {
+		t.Fatalf("expected only foo.go to survive, got %+v", filtered)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// hookOptions bundles the flag values runHookMode needs to build a
+// provider and a suggestion, mirroring the interactive flow in main().
+type hookOptions struct {
+	provider    string
+	endpoint    string
+	model       string
+	timeout     time.Duration
+	maxTokens   int
+	types       string
+	defaultProv string
+}
+
+// runHookMode implements `gocommit -hook <msg_file> <source> <sha>`: it
+// generates a single suggestion non-interactively and writes it into
+// msgFile so `git commit` picks it up as the prepared message. It is a
+// no-op (not an error) whenever there's nothing sensible to suggest:
+// no staged changes, or a source that already carries an explicit
+// message.
+func runHookMode(msgFile string, args []string, opts hookOptions) error {
+	source := ""
+	if len(args) > 0 {
+		source = args[0]
+	}
+	if skipHookSource(source) {
+		return nil
+	}
+
+	if err := ensureGitRepo(); err != nil {
+		return err
+	}
+
+	diff, err := stagedDiff()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return nil
+	}
+	stat, err := stagedDiffStat()
+	if err != nil {
+		return err
+	}
+	stagedFiles, err := stagedFileNames()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(defaultConfigPath())
+	if err != nil {
+		return err
+	}
+	providerName := opts.provider
+	if cfg.Provider != "" && providerName == opts.defaultProv && os.Getenv("GOCOMMIT_PROVIDER") == "" {
+		providerName = cfg.Provider
+	}
+
+	p, err := newProvider(providerName, cfg, opts.endpoint, opts.model, opts.timeout, false, false)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	root, err := repoRoot()
+	if err != nil {
+		return err
+	}
+	diff, err = budgetDiff(ctx, p, diff, root, opts.maxTokens)
+	if err != nil {
+		return err
+	}
+
+	suggestions, err := p.Suggest(ctx, diff, stat, 1, defaultTemperature)
+	if err != nil {
+		return err
+	}
+	if len(suggestions) == 0 {
+		return errors.New("no suggestions returned")
+	}
+
+	allowedTypes := parseCommitTypes(opts.types)
+	suggestion := normalizeCommitSuggestion(suggestions[0], allowedTypes, stagedFiles)
+
+	return os.WriteFile(msgFile, []byte(renderCommitMessage(suggestion)+"\n"), 0o644)
+}
+
+// skipHookSource reports whether prepare-commit-msg's source argument
+// means a message already exists and gocommit shouldn't overwrite it:
+// an explicit -m/-F message, merges, squashes, and commits that already
+// carry a message (-c, -C, or a cherry-pick/amend, which git reports as
+// source "commit").
+func skipHookSource(source string) bool {
+	switch source {
+	case "message", "merge", "squash", "commit":
+		return true
+	default:
+		return false
+	}
+}
+
+// installHook writes a prepare-commit-msg shim into the current repo's
+// .git/hooks that re-invokes this same binary in -hook mode.
+func installHook() error {
+	root, err := repoRoot()
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate gocommit binary: %w", err)
+	}
+
+	hookPath := filepath.Join(root, ".git", "hooks", "prepare-commit-msg")
+	script := fmt.Sprintf("#!/bin/sh\nexec %q -hook \"$1\" \"$2\" \"$3\"\n", exe)
+	if err := os.WriteFile(hookPath, []byte(script), 0o755); err != nil {
+		return fmt.Errorf("write hook: %w", err)
+	}
+
+	fmt.Println("Installed prepare-commit-msg hook at", hookPath)
+	return nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestSkipHookSource(t *testing.T) {
+	cases := map[string]bool{
+		"message":  true,
+		"template": false,
+		"merge":    true,
+		"squash":   true,
+		"commit":   true,
+	}
+	for source, want := range cases {
+		if got := skipHookSource(source); got != want {
+			t.Errorf("skipHookSource(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
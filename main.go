@@ -3,50 +3,67 @@ package main
 import (
 	"bufio"
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
 	"time"
 )
 
-type ollamaChatRequest struct {
-	Model    string        `json:"model"`
-	Messages []chatMessage `json:"messages"`
-	Stream   bool          `json:"stream"`
-	Format   any           `json:"format,omitempty"`
-}
-
-type chatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type ollamaChatResponse struct {
-	Message chatMessage `json:"message"`
-}
-
-type suggestionsPayload struct {
-	Messages []string `json:"messages"`
-}
+// exitCanceled is returned when the user aborts an in-flight request
+// with Ctrl-C; it is distinct from the generic failure exit code so
+// scripts can tell "aborted" apart from "errored".
+const exitCanceled = 130
 
 func main() {
-	defaultEndpoint := envOr("OLLAMA_ENDPOINT", "http://localhost:11434")
-	defaultModel := envOr("OLLAMA_MODEL", "llama3.1")
+	if len(os.Args) > 1 && os.Args[1] == "install-hook" {
+		if err := installHook(); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	defaultProvider := envOr("GOCOMMIT_PROVIDER", "ollama")
 
 	var (
-		endpoint = flag.String("endpoint", defaultEndpoint, "Ollama endpoint (or OLLAMA_ENDPOINT)")
-		model    = flag.String("model", defaultModel, "Ollama model (or OLLAMA_MODEL)")
-		count    = flag.Int("n", 3, "number of suggestions (1-3)")
-		timeout  = flag.Duration("timeout", 30*time.Second, "HTTP timeout")
+		provider  = flag.String("provider", defaultProvider, "provider to use: ollama, openai, anthropic, gemini, llamacpp (or GOCOMMIT_PROVIDER)")
+		endpoint  = flag.String("endpoint", "", "provider endpoint override")
+		model     = flag.String("model", "", "provider model override")
+		count     = flag.Int("n", 3, "number of suggestions (1-3)")
+		timeout   = flag.Duration("timeout", 30*time.Second, "request timeout")
+		stream    = flag.Bool("stream", false, "stream tokens as they arrive (ollama only)")
+		agent     = flag.Bool("agent", false, "let the model inspect the repo with tools before answering (ollama only)")
+		types     = flag.String("types", strings.Join(defaultCommitTypes, ","), "comma-separated allow-list of Conventional Commits types")
+		maxTokens = flag.Int("max-tokens", 8000, "token budget for the staged diff before hunks get summarized (0 disables)")
+		hookFile  = flag.String("hook", "", "run as a prepare-commit-msg hook, writing the suggestion to this message file")
 	)
 	flag.Parse()
 
+	if *hookFile != "" {
+		opts := hookOptions{
+			provider:    *provider,
+			endpoint:    *endpoint,
+			model:       *model,
+			timeout:     *timeout,
+			maxTokens:   *maxTokens,
+			types:       *types,
+			defaultProv: defaultProvider,
+		}
+		if err := runHookMode(*hookFile, flag.Args(), opts); err != nil {
+			if errors.Is(err, context.Canceled) {
+				fmt.Fprintln(os.Stderr, "canceled")
+				os.Exit(exitCanceled)
+			}
+			fatal(err)
+		}
+		return
+	}
+
 	if *count < 1 {
 		*count = 1
 	}
@@ -69,25 +86,83 @@ func main() {
 	if err != nil {
 		fatal(err)
 	}
+	stagedFiles, err := stagedFileNames()
+	if err != nil {
+		fatal(err)
+	}
+
+	cfg, err := loadConfig(defaultConfigPath())
+	if err != nil {
+		fatal(err)
+	}
+	if cfg.Provider != "" && *provider == defaultProvider && os.Getenv("GOCOMMIT_PROVIDER") == "" {
+		*provider = cfg.Provider
+	}
+
+	p, err := newProvider(*provider, cfg, *endpoint, *model, *timeout, *stream, *agent)
+	if err != nil {
+		fatal(err)
+	}
 
-	msgs, err := requestSuggestions(*endpoint, *model, *count, diff, stat, *timeout)
+	root, err := repoRoot()
 	if err != nil {
 		fatal(err)
 	}
-	if len(msgs) == 0 {
+
+	// The signal-derived context is scoped tightly to these two network
+	// calls (and, later, each TUI regenerate), not held for the rest of
+	// main: signal.NotifyContext disables the OS's default
+	// terminate-on-SIGINT behavior for as long as it's registered, and
+	// nothing reads ctx.Done() while blocked on the interactive prompt's
+	// stdin reads, so Ctrl-C would otherwise stop working entirely once
+	// the model has responded.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	diff, err = budgetDiff(ctx, p, diff, root, *maxTokens)
+	if err != nil {
+		stop()
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "canceled")
+			os.Exit(exitCanceled)
+		}
+		fatal(err)
+	}
+
+	suggestions, err := p.Suggest(ctx, diff, stat, *count, defaultTemperature)
+	stop()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "canceled")
+			os.Exit(exitCanceled)
+		}
+		fatal(err)
+	}
+	if len(suggestions) == 0 {
 		fatal(errors.New("no suggestions returned"))
 	}
 
-	chosen, err := chooseMessage(msgs)
+	allowedTypes := parseCommitTypes(*types)
+	for i, cs := range suggestions {
+		suggestions[i] = normalizeCommitSuggestion(cs, allowedTypes, stagedFiles)
+	}
+
+	chosen, amend, err := chooseMessageInteractive(p, diff, stat, suggestions, allowedTypes, stagedFiles)
 	if err != nil {
+		if errors.Is(err, errAborted) {
+			fmt.Fprintln(os.Stderr, "aborted")
+			os.Exit(exitCanceled)
+		}
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "canceled")
+			os.Exit(exitCanceled)
+		}
 		fatal(err)
 	}
 
-	if err := gitCommit(chosen); err != nil {
+	if err := gitCommit(chosen, amend); err != nil {
 		fatal(err)
 	}
 
-	fmt.Println("Committed:", chosen)
+	fmt.Println("Committed:", strings.SplitN(chosen, "\n", 2)[0])
 }
 
 func ensureGitRepo() error {
@@ -122,122 +197,28 @@ func stagedDiffStat() (string, error) {
 	return out.String(), nil
 }
 
-func requestSuggestions(endpoint, model string, n int, diff, stat string, timeout time.Duration) ([]string, error) {
-	prompt := buildPrompt(diff, stat)
-
-	reqBody := ollamaChatRequest{
-		Model: model,
-		Messages: []chatMessage{
-			{Role: "system", Content: "You write concise git commit messages."},
-			{Role: "user", Content: prompt},
-		},
-		Stream: false,
-		Format: map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"messages": map[string]any{
-					"type":     "array",
-					"items":    map[string]any{"type": "string"},
-					"minItems": 1,
-					"maxItems": n,
-				},
-			},
-			"required": []string{"messages"},
-		},
-	}
-
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	client := &http.Client{Timeout: timeout}
-	url := strings.TrimRight(endpoint, "/") + "/api/chat"
-	resp, err := client.Post(url, "application/json", bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("ollama request: %w", err)
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode >= 400 {
-		b, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("ollama error %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
-	}
-
-	var out ollamaChatResponse
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&out); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-
-	msgs, err := parseSuggestions(out.Message.Content)
-	if err != nil {
-		return nil, err
-	}
-	if len(msgs) > n {
-		msgs = msgs[:n]
-	}
-	return msgs, nil
-}
-
-func buildPrompt(diff, stat string) string {
-	return fmt.Sprintf(
-		"You MUST only describe the staged diff. Do NOT invent changes. "+
-			"Use imperative present tense. One line per suggestion. "+
-			"If changes are only comments/whitespace/formatting, say so explicitly. "+
-			"Return ONLY JSON with shape {\"messages\": [\"...\"]}.\n\n"+
-			"Staged diff stat:\n%s\n\nStaged diff:\n%s",
-		stat,
-		diff,
-	)
-}
-
-func parseSuggestions(content string) ([]string, error) {
-	content = strings.TrimSpace(content)
-	if content == "" {
-		return nil, errors.New("empty response from model")
-	}
-
-	var payload suggestionsPayload
-	if err := json.Unmarshal([]byte(content), &payload); err == nil && len(payload.Messages) > 0 {
-		return normalizeMessages(payload.Messages), nil
+func stagedFileNames() ([]string, error) {
+	cmd := exec.Command("git", "diff", "--staged", "--name-only")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git diff --staged --name-only failed: %w", err)
 	}
-
-	// Fallback: split lines
-	lines := strings.Split(content, "\n")
-	var msgs []string
-	for _, line := range lines {
-		line = strings.TrimSpace(strings.TrimLeft(line, "-0123456789. "))
+	var files []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		line = strings.TrimSpace(line)
 		if line != "" {
-			msgs = append(msgs, line)
+			files = append(files, line)
 		}
 	}
-	if len(msgs) == 0 {
-		return nil, errors.New("could not parse suggestions")
-	}
-	return normalizeMessages(msgs), nil
+	return files, nil
 }
 
-func normalizeMessages(msgs []string) []string {
-	out := make([]string, 0, len(msgs))
-	seen := map[string]bool{}
-	for _, m := range msgs {
-		m = strings.TrimSpace(m)
-		if m == "" || seen[m] {
-			continue
-		}
-		seen[m] = true
-		out = append(out, m)
-	}
-	return out
-}
-
-func chooseMessage(msgs []string) (string, error) {
+func chooseMessage(suggestions []commitSuggestion) (string, error) {
 	fmt.Println("Suggestions:")
-	for i, m := range msgs {
-		fmt.Printf("%d. %s\n", i+1, m)
+	for i, cs := range suggestions {
+		fmt.Printf("%d. %s\n", i+1, commitHeader(cs))
 	}
 	fmt.Println("Choose 1-3 or type 'e' to edit:")
 
@@ -255,12 +236,12 @@ func chooseMessage(msgs []string) (string, error) {
 		if line == "" {
 			continue
 		}
-		idx, err := parseChoice(line, len(msgs))
+		idx, err := parseChoice(line, len(suggestions))
 		if err != nil {
 			fmt.Println("Invalid choice. Try again.")
 			continue
 		}
-		return msgs[idx], nil
+		return renderCommitMessage(suggestions[idx]), nil
 	}
 }
 
@@ -294,8 +275,13 @@ func promptEdit(reader *bufio.Reader) (string, error) {
 	}
 }
 
-func gitCommit(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
+func gitCommit(message string, amend bool) error {
+	args := []string{"commit", "-F", "-"}
+	if amend {
+		args = []string{"commit", "--amend", "-F", "-"}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(message)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -3,7 +3,7 @@ package main
 import "testing"
 
 func TestParseSuggestionsJSON(t *testing.T) {
-	content := `{"messages":["feat: add x","fix: bug y"]}`
+	content := `{"suggestions":[{"type":"feat","subject":"add x"},{"type":"fix","subject":"bug y"}]}`
 	msgs, err := parseSuggestions(content)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -11,8 +11,8 @@ func TestParseSuggestionsJSON(t *testing.T) {
 	if len(msgs) != 2 {
 		t.Fatalf("expected 2 messages, got %d", len(msgs))
 	}
-	if msgs[0] != "feat: add x" {
-		t.Fatalf("unexpected first message: %q", msgs[0])
+	if msgs[0].Type != "feat" || msgs[0].Subject != "add x" {
+		t.Fatalf("unexpected first message: %+v", msgs[0])
 	}
 }
 
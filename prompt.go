@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+func buildPrompt(diff, stat string) string {
+	return "You MUST only describe the staged diff. Do NOT invent changes. " +
+		"Follow Conventional Commits: pick a type from feat, fix, docs, refactor, test, chore, perf, build, ci; " +
+		"infer a scope from the changed paths when it's obvious, and omit it otherwise; " +
+		"write the subject in imperative present tense; " +
+		"if the change is breaking, set breaking to true and explain why in body. " +
+		"If changes are only comments/whitespace/formatting, say so explicitly in the subject. " +
+		"Return ONLY JSON with shape " +
+		`{"suggestions": [{"type": "...", "scope": "...", "subject": "...", "body": "...", "breaking": false, "footers": ["..."]}]}` +
+		".\n\n" +
+		"Staged diff stat:\n" + stat + "\n\nStaged diff:\n" + diff
+}
+
+// parseSuggestions decodes a model's response into commit suggestions,
+// preferring the structured {"suggestions": [...]} payload and falling
+// back to treating each non-empty line as a bare subject when the model
+// didn't (or couldn't) follow the JSON instructions.
+func parseSuggestions(content string) ([]commitSuggestion, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("empty response from model")
+	}
+
+	var payload suggestionsPayload
+	if err := json.Unmarshal([]byte(content), &payload); err == nil && len(payload.Suggestions) > 0 {
+		return normalizeSuggestions(payload.Suggestions), nil
+	}
+
+	// Fallback: split lines, treating each as a bare subject.
+	lines := strings.Split(content, "\n")
+	var suggestions []commitSuggestion
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.TrimLeft(line, "-0123456789. "))
+		if line == "" {
+			continue
+		}
+		suggestions = append(suggestions, commitSuggestion{Type: "chore", Subject: line})
+	}
+	if len(suggestions) == 0 {
+		return nil, errors.New("could not parse suggestions")
+	}
+	return normalizeSuggestions(suggestions), nil
+}
+
+func normalizeSuggestions(suggestions []commitSuggestion) []commitSuggestion {
+	out := make([]commitSuggestion, 0, len(suggestions))
+	seen := map[string]bool{}
+	for _, cs := range suggestions {
+		cs.Subject = strings.TrimSpace(cs.Subject)
+		if cs.Subject == "" || seen[cs.Subject] {
+			continue
+		}
+		seen[cs.Subject] = true
+		out = append(out, cs)
+	}
+	return out
+}
+
+// suggestionsFormatSchema is the JSON-schema passed as Ollama's `format`
+// constraint so the model's response can be decoded straight into
+// suggestionsPayload.
+func suggestionsFormatSchema(n int) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"suggestions": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"type":     map[string]any{"type": "string"},
+						"scope":    map[string]any{"type": "string"},
+						"subject":  map[string]any{"type": "string"},
+						"body":     map[string]any{"type": "string"},
+						"breaking": map[string]any{"type": "boolean"},
+						"footers":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					},
+					"required": []string{"type", "subject"},
+				},
+				"minItems": 1,
+				"maxItems": n,
+			},
+		},
+		"required": []string{"suggestions"},
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Provider generates commit message suggestions for a staged diff.
+type Provider interface {
+	// Suggest returns up to n commit suggestions for the given diff.
+	// temperature is the sampling temperature to request from the model;
+	// pass defaultTemperature for a provider's normal behavior, or a
+	// higher value (e.g. from the TUI's regenerate key) for more varied
+	// suggestions.
+	Suggest(ctx context.Context, diff, stat string, n int, temperature float64) ([]commitSuggestion, error)
+
+	// Summarize answers a single free-form prompt with plain text. It
+	// backs the diff-budgeting map-reduce pass, which asks the model to
+	// condense oversized hunks before they ever reach buildPrompt.
+	Summarize(ctx context.Context, prompt string) (string, error)
+}
+
+// defaultTemperature is passed to Suggest for a normal (non-regenerate)
+// request; providers that don't support tuning sampling ignore it.
+const defaultTemperature = 0.2
+
+// regenerateTemperature is used by the TUI's 'r' key to ask for more
+// varied suggestions than the default pass.
+const regenerateTemperature = 0.9
+
+// providerConfig holds the per-provider settings loaded from the config
+// file and/or overridden by the -endpoint and -model flags.
+type providerConfig struct {
+	Endpoint string
+	APIKey   string
+	Model    string
+	Binary   string
+}
+
+// config is the parsed contents of ~/.config/gocommit/config.yaml.
+type config struct {
+	Provider  string
+	Providers map[string]providerConfig
+}
+
+func defaultConfigPath() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "gocommit", "config.yaml")
+	}
+	return ""
+}
+
+// cfgSection is one entry in loadConfig's indentation stack: a key that
+// introduced a nested block, and the indentation column it was read at.
+type cfgSection struct {
+	indent int
+	name   string
+}
+
+// loadConfig reads the small indented "section: key: value" config file
+// used to store per-provider endpoints, models and API keys. A missing
+// file is not an error; callers fall back to flag/env defaults.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{Providers: map[string]providerConfig{}}
+	if path == "" {
+		return cfg, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("open config: %w", err)
+	}
+	defer f.Close()
+
+	// stack holds the chain of ancestor section keys (e.g. "providers",
+	// then a provider name) keyed by the indentation column each was
+	// read at, so a line returns to the right section by comparing
+	// indentation depth rather than a single flat string.
+	var stack []cfgSection
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		// Pop back out of any sections at this indentation or deeper;
+		// they're siblings or ancestors we've returned past.
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		if value == "" {
+			// A key with no value introduces a nested block, e.g.
+			// "providers:" or a provider name underneath it.
+			if len(stack) == 1 && stack[0].name == "providers" {
+				cfg.Providers[key] = providerConfig{}
+			}
+			stack = append(stack, cfgSection{indent: indent, name: key})
+			continue
+		}
+
+		switch len(stack) {
+		case 0:
+			if key == "provider" {
+				cfg.Provider = value
+			}
+		case 2:
+			if stack[0].name != "providers" {
+				continue
+			}
+			name := stack[1].name
+			pc := cfg.Providers[name]
+			switch key {
+			case "endpoint":
+				pc.Endpoint = value
+			case "api_key":
+				pc.APIKey = value
+			case "model":
+				pc.Model = value
+			case "binary":
+				pc.Binary = value
+			}
+			cfg.Providers[name] = pc
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	return cfg, nil
+}
+
+// newProvider builds the Provider named by name, applying config file
+// settings first and the -endpoint/-model flag overrides on top.
+func newProvider(name string, cfg *config, endpoint, model string, timeout time.Duration, stream, useTools bool) (Provider, error) {
+	pc := cfg.Providers[name]
+	if endpoint != "" {
+		pc.Endpoint = endpoint
+	}
+	if model != "" {
+		pc.Model = model
+	}
+
+	switch name {
+	case "ollama":
+		if pc.Endpoint == "" {
+			pc.Endpoint = "http://localhost:11434"
+		}
+		if pc.Model == "" {
+			pc.Model = "llama3.1"
+		}
+		return &ollamaProvider{endpoint: pc.Endpoint, model: pc.Model, timeout: timeout, stream: stream, useTools: useTools}, nil
+	case "openai":
+		if pc.Endpoint == "" {
+			pc.Endpoint = "https://api.openai.com/v1"
+		}
+		if pc.Model == "" {
+			pc.Model = "gpt-4o-mini"
+		}
+		return &openAIProvider{endpoint: pc.Endpoint, apiKey: pc.APIKey, model: pc.Model, timeout: timeout}, nil
+	case "anthropic":
+		if pc.Endpoint == "" {
+			pc.Endpoint = "https://api.anthropic.com/v1"
+		}
+		if pc.Model == "" {
+			pc.Model = "claude-3-5-sonnet-latest"
+		}
+		return &anthropicProvider{endpoint: pc.Endpoint, apiKey: pc.APIKey, model: pc.Model, timeout: timeout}, nil
+	case "gemini":
+		if pc.Endpoint == "" {
+			pc.Endpoint = "https://generativelanguage.googleapis.com/v1beta"
+		}
+		if pc.Model == "" {
+			pc.Model = "gemini-1.5-flash"
+		}
+		return &geminiProvider{endpoint: pc.Endpoint, apiKey: pc.APIKey, model: pc.Model, timeout: timeout}, nil
+	case "llamacpp":
+		if pc.Binary == "" {
+			pc.Binary = "llama"
+		}
+		return &llamaCppProvider{binary: pc.Binary, model: pc.Model, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type anthropicMessagesRequest struct {
+	Model       string                  `json:"model"`
+	MaxTokens   int                     `json:"max_tokens"`
+	System      string                  `json:"system,omitempty"`
+	Messages    []anthropicInputMessage `json:"messages"`
+	Temperature float64                 `json:"temperature,omitempty"`
+}
+
+type anthropicInputMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicMessagesResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// anthropicProvider talks to the Anthropic Messages API.
+type anthropicProvider struct {
+	endpoint string
+	apiKey   string
+	model    string
+	timeout  time.Duration
+}
+
+func (p *anthropicProvider) Suggest(ctx context.Context, diff, stat string, n int, temperature float64) ([]commitSuggestion, error) {
+	content, err := p.chat(ctx, "You write concise git commit messages.", buildPrompt(diff, stat), temperature)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := parseSuggestions(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > n {
+		msgs = msgs[:n]
+	}
+	return msgs, nil
+}
+
+// Summarize asks the model to answer a single free-form prompt.
+func (p *anthropicProvider) Summarize(ctx context.Context, prompt string) (string, error) {
+	return p.chat(ctx, "", prompt, defaultTemperature)
+}
+
+func (p *anthropicProvider) chat(ctx context.Context, system, userPrompt string, temperature float64) (string, error) {
+	reqBody := anthropicMessagesRequest{
+		Model:     p.model,
+		MaxTokens: 1024,
+		System:    system,
+		Messages: []anthropicInputMessage{
+			{Role: "user", Content: userPrompt},
+		},
+		Temperature: temperature,
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(p.endpoint, "/") + "/messages"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+	if p.apiKey != "" {
+		httpReq.Header.Set("x-api-key", p.apiKey)
+	}
+
+	client := &http.Client{Timeout: p.timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("anthropic request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("anthropic error %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var out anthropicMessagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if len(out.Content) == 0 {
+		return "", fmt.Errorf("anthropic returned no content")
+	}
+	return out.Content[0].Text, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type geminiGenerateRequest struct {
+	Contents         []geminiContent        `json:"contents"`
+	GenerationConfig geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiGenerateResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+}
+
+// geminiProvider talks to Google's Gemini generateContent API.
+type geminiProvider struct {
+	endpoint string
+	apiKey   string
+	model    string
+	timeout  time.Duration
+}
+
+func (p *geminiProvider) Suggest(ctx context.Context, diff, stat string, n int, temperature float64) ([]commitSuggestion, error) {
+	content, err := p.chat(ctx, "You write concise git commit messages.\n\n"+buildPrompt(diff, stat), temperature)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := parseSuggestions(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > n {
+		msgs = msgs[:n]
+	}
+	return msgs, nil
+}
+
+// Summarize asks the model to answer a single free-form prompt.
+func (p *geminiProvider) Summarize(ctx context.Context, prompt string) (string, error) {
+	return p.chat(ctx, prompt, defaultTemperature)
+}
+
+func (p *geminiProvider) chat(ctx context.Context, prompt string, temperature float64) (string, error) {
+	reqBody := geminiGenerateRequest{
+		Contents: []geminiContent{
+			{Parts: []geminiPart{{Text: prompt}}},
+		},
+		GenerationConfig: geminiGenerationConfig{Temperature: temperature},
+	}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/models/%s:generateContent?key=%s",
+		strings.TrimRight(p.endpoint, "/"), p.model, url.QueryEscape(p.apiKey))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: p.timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("gemini request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("gemini error %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var out geminiGenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if len(out.Candidates) == 0 || len(out.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("gemini returned no candidates")
+	}
+	return out.Candidates[0].Content.Parts[0].Text, nil
+}
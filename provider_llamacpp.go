@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// llamaCppProvider shells out to a local `llama` (llama.cpp) binary,
+// passing the model path and prompt as flags and reading the generated
+// text back from stdout. There is no structured-output support here, so
+// buildPrompt's prompt-level JSON instructions carry the whole load.
+type llamaCppProvider struct {
+	binary  string
+	model   string
+	timeout time.Duration
+}
+
+func (p *llamaCppProvider) Suggest(ctx context.Context, diff, stat string, n int, temperature float64) ([]commitSuggestion, error) {
+	out, err := p.run(ctx, "You write concise git commit messages.\n\n"+buildPrompt(diff, stat), temperature)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := parseSuggestions(out)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > n {
+		msgs = msgs[:n]
+	}
+	return msgs, nil
+}
+
+// Summarize asks the model to answer a single free-form prompt.
+func (p *llamaCppProvider) Summarize(ctx context.Context, prompt string) (string, error) {
+	return p.run(ctx, prompt, defaultTemperature)
+}
+
+func (p *llamaCppProvider) run(ctx context.Context, prompt string, temperature float64) (string, error) {
+	runCtx := ctx
+	var cancel context.CancelFunc
+	if p.timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	args := []string{"-p", prompt, "--temp", strconv.FormatFloat(temperature, 'f', -1, 64)}
+	if p.model != "" {
+		args = append(args, "-m", p.model)
+	}
+
+	cmd := exec.CommandContext(runCtx, p.binary, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("llama.cpp run: %w: %s", err, stderr.String())
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type ollamaChatRequest struct {
+	Model    string         `json:"model"`
+	Messages []chatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Format   any            `json:"format,omitempty"`
+	Tools    []ollamaTool   `json:"tools,omitempty"`
+	Options  map[string]any `json:"options,omitempty"`
+}
+
+type chatMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message chatMessage `json:"message"`
+}
+
+// ollamaProvider talks to a local Ollama server's /api/chat endpoint. It is
+// the only provider that gets the JSON-schema `format` constraint, since
+// Ollama is the one backend here with structured-output support; the
+// others rely on the prompt-level JSON instructions in buildPrompt.
+type ollamaProvider struct {
+	endpoint string
+	model    string
+	timeout  time.Duration
+	stream   bool
+	useTools bool
+}
+
+func (p *ollamaProvider) Suggest(ctx context.Context, diff, stat string, n int, temperature float64) ([]commitSuggestion, error) {
+	if p.useTools {
+		return p.suggestWithTools(ctx, diff, stat, n, temperature)
+	}
+
+	prompt := buildPrompt(diff, stat)
+
+	reqBody := ollamaChatRequest{
+		Model: p.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: "You write concise git commit messages."},
+			{Role: "user", Content: prompt},
+		},
+		Stream:  p.stream,
+		Format:  suggestionsFormatSchema(n),
+		Options: map[string]any{"temperature": temperature},
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(p.endpoint, "/") + "/api/chat"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Streaming responses can legitimately run longer than the configured
+	// request timeout, so cancellation is left entirely to ctx there;
+	// non-streaming requests still get the blanket client timeout.
+	client := &http.Client{}
+	if !p.stream {
+		client.Timeout = p.timeout
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("ollama request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama error %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var content string
+	if p.stream {
+		content, err = readStreamedContent(ctx, resp.Body)
+	} else {
+		var out ollamaChatResponse
+		dec := json.NewDecoder(resp.Body)
+		if decErr := dec.Decode(&out); decErr != nil {
+			return nil, fmt.Errorf("decode response: %w", decErr)
+		}
+		content = out.Message.Content
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := parseSuggestions(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > n {
+		msgs = msgs[:n]
+	}
+	return msgs, nil
+}
+
+// readStreamedContent consumes the newline-delimited JSON chunks Ollama
+// sends when `stream: true`, printing each token as it arrives so the
+// user sees progress on large diffs, and returns the concatenated
+// content once the stream ends (the response body reaches EOF).
+//
+// The scan runs in its own goroutine so a context cancellation (e.g.
+// Ctrl-C) can interrupt the wait immediately instead of blocking on the
+// next read; that goroutine closes its result channel exactly once, via
+// a single deferred close, regardless of which exit path it takes.
+func readStreamedContent(ctx context.Context, body io.Reader) (string, error) {
+	type chunkResult struct {
+		content string
+		err     error
+	}
+	done := make(chan chunkResult, 1)
+
+	go func() {
+		var content strings.Builder
+		var sendErr error
+		defer func() {
+			done <- chunkResult{content: content.String(), err: sendErr}
+			close(done)
+		}()
+
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			var chunk ollamaChatResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				sendErr = fmt.Errorf("decode stream chunk: %w", err)
+				return
+			}
+			fmt.Fprint(os.Stderr, chunk.Message.Content)
+			content.WriteString(chunk.Message.Content)
+		}
+		if err := scanner.Err(); err != nil {
+			sendErr = fmt.Errorf("read stream: %w", err)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case res := <-done:
+		if res.err != nil {
+			return "", res.err
+		}
+		return res.content, nil
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type openAIChatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature,omitempty"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// openAIProvider talks to any OpenAI-compatible chat completions endpoint.
+type openAIProvider struct {
+	endpoint string
+	apiKey   string
+	model    string
+	timeout  time.Duration
+}
+
+func (p *openAIProvider) Suggest(ctx context.Context, diff, stat string, n int, temperature float64) ([]commitSuggestion, error) {
+	content, err := p.chat(ctx, []chatMessage{
+		{Role: "system", Content: "You write concise git commit messages."},
+		{Role: "user", Content: buildPrompt(diff, stat)},
+	}, temperature)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := parseSuggestions(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > n {
+		msgs = msgs[:n]
+	}
+	return msgs, nil
+}
+
+// Summarize asks the model to answer a single free-form prompt.
+func (p *openAIProvider) Summarize(ctx context.Context, prompt string) (string, error) {
+	return p.chat(ctx, []chatMessage{{Role: "user", Content: prompt}}, defaultTemperature)
+}
+
+func (p *openAIProvider) chat(ctx context.Context, messages []chatMessage, temperature float64) (string, error) {
+	reqBody := openAIChatRequest{Model: p.model, Messages: messages, Temperature: temperature}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := strings.TrimRight(p.endpoint, "/") + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	client := &http.Client{Timeout: p.timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("openai request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("openai error %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var out openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("openai returned no choices")
+	}
+	return out.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	cfg, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Providers) != 0 {
+		t.Fatalf("expected no providers, got %+v", cfg.Providers)
+	}
+}
+
+func TestLoadConfigMultipleProviders(t *testing.T) {
+	path := writeConfig(t, `provider: anthropic
+providers:
+  anthropic:
+    api_key: "key-A"
+    model: claude-3-5-sonnet-latest
+  openai:
+    api_key: "key-B"
+    endpoint: https://api.openai.com/v1
+`)
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Provider != "anthropic" {
+		t.Fatalf("expected default provider %q, got %q", "anthropic", cfg.Provider)
+	}
+	if len(cfg.Providers) != 2 {
+		t.Fatalf("expected 2 providers, got %+v", cfg.Providers)
+	}
+	if got := cfg.Providers["anthropic"]; got.APIKey != "key-A" || got.Model != "claude-3-5-sonnet-latest" {
+		t.Fatalf("unexpected anthropic config: %+v", got)
+	}
+	if got := cfg.Providers["openai"]; got.APIKey != "key-B" || got.Endpoint != "https://api.openai.com/v1" {
+		t.Fatalf("unexpected openai config: %+v", got)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// rawMode holds the terminal attributes captured before switching a file
+// descriptor into raw mode, so they can be restored afterwards.
+type rawMode struct {
+	orig syscall.Termios
+}
+
+// enableRawMode puts fd into character-at-a-time, unechoed input mode
+// and returns the previous settings so the caller can restore them.
+// ISIG is deliberately left set: runTUI's regenerate ('r') call relies
+// on Ctrl-C raising SIGINT and cancelling its context like any other
+// command, so the terminal driver must keep generating it even in raw
+// mode. This is a thin, Linux-only wrapper around the TCGETS/TCSETS
+// ioctls; there is no termios support in the standard library beyond
+// the raw syscall numbers, so the tui falls back to the line-based
+// prompt on any other platform (see tui.go and termios_other.go). A
+// failing ioctl here is returned as an error; it does not trigger that
+// fallback.
+func enableRawMode(fd int) (*rawMode, error) {
+	var t syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TCGETS), uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return nil, errno
+	}
+	orig := t
+
+	t.Lflag &^= syscall.ICANON | syscall.ECHO
+	t.Iflag &^= syscall.IXON | syscall.ICRNL
+	t.Cc[syscall.VMIN] = 1
+	t.Cc[syscall.VTIME] = 0
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TCSETS), uintptr(unsafe.Pointer(&t))); errno != 0 {
+		return nil, errno
+	}
+	return &rawMode{orig: orig}, nil
+}
+
+// restore puts fd's terminal attributes back the way enableRawMode found them.
+func (r *rawMode) restore(fd int) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TCSETS), uintptr(unsafe.Pointer(&r.orig))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+type rawMode struct{}
+
+func enableRawMode(fd int) (*rawMode, error) {
+	return nil, errRawModeUnsupported
+}
+
+func (r *rawMode) restore(fd int) error {
+	return nil
+}
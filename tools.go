@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ollamaTool describes a function the model may call, in the shape the
+// Ollama tools/function-calling API expects.
+type ollamaTool struct {
+	Type     string         `json:"type"`
+	Function ollamaFunction `json:"function"`
+}
+
+type ollamaFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// toolCall is what the model sends back when it wants a tool executed.
+type toolCall struct {
+	Function struct {
+		Name      string `json:"name"`
+		Arguments any    `json:"arguments"`
+	} `json:"function"`
+}
+
+// agentTools is the fixed set of read-only repo-inspection tools offered
+// to the model so it can ground its suggestion in prior commit style and
+// surrounding code, rather than the diff alone.
+var agentTools = []ollamaTool{
+	{
+		Type: "function",
+		Function: ollamaFunction{
+			Name:        "git_log_recent",
+			Description: "List the n most recent commits, newest first, one short hash and subject per line.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"n": map[string]any{"type": "integer", "description": "number of commits to show"},
+				},
+				"required": []string{"n"},
+			},
+		},
+	},
+	{
+		Type: "function",
+		Function: ollamaFunction{
+			Name:        "git_show",
+			Description: "Show the last committed contents of a file at the given repo-relative path.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{"type": "string", "description": "repo-relative file path"},
+				},
+				"required": []string{"path"},
+			},
+		},
+	},
+	{
+		Type: "function",
+		Function: ollamaFunction{
+			Name:        "read_file",
+			Description: "Read the current on-disk contents of a file at the given repo-relative path.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path": map[string]any{"type": "string", "description": "repo-relative file path"},
+				},
+				"required": []string{"path"},
+			},
+		},
+	},
+	{
+		Type: "function",
+		Function: ollamaFunction{
+			Name:        "list_staged_files",
+			Description: "List the repo-relative paths of all currently staged files.",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+	},
+}
+
+// repoRoot returns the absolute path to the top of the working tree, used
+// to confine tool calls that take a path argument.
+func repoRoot() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git rev-parse --show-toplevel failed: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// resolveRepoPath joins a tool-supplied path onto the repo root and
+// rejects anything that escapes it: absolute paths, `../` that Clean
+// resolves outside root, and symlinks (anywhere in the path, including
+// the final component) that resolve outside root. filepath.Clean is
+// purely lexical, so the Clean-and-prefix-check above it only catches
+// `../`-style escapes; a symlink pointing outside root would pass that
+// check and still get read, so symlinks must be resolved separately via
+// EvalSymlinks before trusting containment.
+func resolveRepoPath(root, rel string) (string, error) {
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("path must be repo-relative: %q", rel)
+	}
+	full := filepath.Clean(filepath.Join(root, rel))
+	if full != root && !strings.HasPrefix(full, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path escapes repository root: %q", rel)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("resolve repository root: %w", err)
+	}
+	realFull, err := filepath.EvalSymlinks(full)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Nothing to read yet, so nothing to escape through.
+			return full, nil
+		}
+		return "", fmt.Errorf("resolve path: %w", err)
+	}
+	if realFull != realRoot && !strings.HasPrefix(realFull, realRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("path escapes repository root: %q", rel)
+	}
+	return realFull, nil
+}
+
+// executeTool runs the named tool with the given JSON-decoded arguments
+// and returns the text to feed back to the model as a `role: "tool"`
+// message.
+func executeTool(name string, args any) (string, error) {
+	argMap, _ := args.(map[string]any)
+
+	switch name {
+	case "git_log_recent":
+		n := 10
+		if v, ok := argMap["n"].(float64); ok && v > 0 {
+			n = int(v)
+		}
+		cmd := exec.Command("git", "log", fmt.Sprintf("-n%d", n), "--oneline")
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git log failed: %w", err)
+		}
+		return out.String(), nil
+
+	case "git_show":
+		path, _ := argMap["path"].(string)
+		root, err := repoRoot()
+		if err != nil {
+			return "", err
+		}
+		if _, err := resolveRepoPath(root, path); err != nil {
+			return "", err
+		}
+		cmd := exec.Command("git", "show", "HEAD:"+path)
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git show failed: %w", err)
+		}
+		return out.String(), nil
+
+	case "read_file":
+		path, _ := argMap["path"].(string)
+		root, err := repoRoot()
+		if err != nil {
+			return "", err
+		}
+		full, err := resolveRepoPath(root, path)
+		if err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(full)
+		if err != nil {
+			return "", fmt.Errorf("read file: %w", err)
+		}
+		return string(b), nil
+
+	case "list_staged_files":
+		cmd := exec.Command("git", "diff", "--staged", "--name-only")
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git diff --staged --name-only failed: %w", err)
+		}
+		return out.String(), nil
+
+	default:
+		return "", fmt.Errorf("unknown tool %q", name)
+	}
+}
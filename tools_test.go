@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveRepoPathRejectsAbsolute(t *testing.T) {
+	if _, err := resolveRepoPath(t.TempDir(), "/etc/passwd"); err == nil {
+		t.Fatal("expected error for absolute path")
+	}
+}
+
+func TestResolveRepoPathRejectsDotDotEscape(t *testing.T) {
+	root := t.TempDir()
+	if _, err := resolveRepoPath(root, "../escape"); err == nil {
+		t.Fatal("expected error for path escaping root")
+	}
+}
+
+func TestResolveRepoPathRejectsSymlinkEscape(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("top secret"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	link := filepath.Join(root, "evil_link")
+	if err := os.Symlink(secret, link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if _, err := resolveRepoPath(root, "evil_link"); err == nil {
+		t.Fatal("expected error for symlink escaping repository root")
+	}
+}
+
+func TestResolveRepoPathAllowsPathsInsideRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "file.go"), []byte("package main"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	got, err := resolveRepoPath(root, "file.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Base(got) != "file.go" {
+		t.Fatalf("unexpected resolved path: %q", got)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+)
+
+// errAborted is returned by the interactive chooser when the user quits
+// without picking a suggestion (the 'q' key in the TUI).
+var errAborted = errors.New("aborted")
+
+// errRawModeUnsupported signals the caller to fall back to the
+// line-based prompt; raw mode is only wired up for Linux (see
+// termios_linux.go and termios_other.go).
+var errRawModeUnsupported = errors.New("raw terminal mode not supported on this platform")
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiGreen = "\x1b[32m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+	ansiBold  = "\x1b[1m"
+)
+
+// maxDiffPreviewLines caps how much of the staged diff the TUI renders,
+// so a huge diff doesn't blow past the terminal's scrollback in one go.
+const maxDiffPreviewLines = 30
+
+// chooseMessageInteractive picks how to gather the user's choice of
+// commit message: the raw-terminal TUI when stdin/stdout are both a
+// TTY, falling back to the plain line-based prompt otherwise (piped
+// input, CI, or a platform without raw-mode support). It returns the
+// rendered commit message and whether the caller asked to amend
+// instead of creating a new commit.
+func chooseMessageInteractive(p Provider, diff, stat string, suggestions []commitSuggestion, allowedTypes []string, stagedFiles []string) (string, bool, error) {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		msg, err := chooseMessage(suggestions)
+		return msg, false, err
+	}
+
+	msg, amend, err := runTUI(p, diff, stat, suggestions, allowedTypes, stagedFiles)
+	if errors.Is(err, errRawModeUnsupported) {
+		msg, err = chooseMessage(suggestions)
+		return msg, false, err
+	}
+	return msg, amend, err
+}
+
+// isTerminal reports whether f is connected to a character device, i.e.
+// an interactive terminal rather than a pipe or redirected file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// runTUI drives the two-pane suggestion picker: a numbered list of
+// suggestions on top and a colorized preview of the staged diff below
+// it. j/k move the selection, enter commits it, 'e' opens it in
+// $EDITOR, 'r' regenerates the suggestions at a higher sampling
+// temperature, 'a' amends the previous commit instead of creating a
+// new one, and 'q' aborts.
+func runTUI(p Provider, diff, stat string, suggestions []commitSuggestion, allowedTypes []string, stagedFiles []string) (string, bool, error) {
+	fd := int(os.Stdin.Fd())
+	mode, err := enableRawMode(fd)
+	if err != nil {
+		return "", false, err
+	}
+	defer func() {
+		_ = mode.restore(fd)
+	}()
+
+	diffView := colorizeDiff(diff)
+	idx := 0
+	count := len(suggestions)
+
+	for {
+		renderTUI(suggestions, idx, diffView)
+
+		key, err := readKey(os.Stdin)
+		if err != nil {
+			return "", false, fmt.Errorf("read key: %w", err)
+		}
+
+		switch key {
+		case 'j':
+			if idx < len(suggestions)-1 {
+				idx++
+			}
+		case 'k':
+			if idx > 0 {
+				idx--
+			}
+		case '\r', '\n':
+			return renderCommitMessage(suggestions[idx]), false, nil
+		case 'a':
+			return renderCommitMessage(suggestions[idx]), true, nil
+		case 'e':
+			edited, err := editMessage(mode, fd, renderCommitMessage(suggestions[idx]))
+			if err != nil {
+				return "", false, err
+			}
+			return edited, false, nil
+		case 'r':
+			// Scope the signal-derived context to just this network
+			// call: leaving it registered while back in readKey would
+			// disable the OS's default terminate-on-SIGINT behavior
+			// without anything reading ctx.Done() to unblock it.
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			regenerated, err := p.Suggest(ctx, diff, stat, count, regenerateTemperature)
+			stop()
+			if err != nil {
+				return "", false, fmt.Errorf("regenerate: %w", err)
+			}
+			for i, cs := range regenerated {
+				regenerated[i] = normalizeCommitSuggestion(cs, allowedTypes, stagedFiles)
+			}
+			if len(regenerated) > 0 {
+				suggestions = regenerated
+				if idx >= len(suggestions) {
+					idx = len(suggestions) - 1
+				}
+			}
+		case 'q':
+			return "", false, errAborted
+		}
+	}
+}
+
+// renderTUI clears the screen and redraws the suggestion list and diff
+// preview, with the currently selected suggestion marked.
+func renderTUI(suggestions []commitSuggestion, idx int, diffView string) {
+	var b strings.Builder
+	b.WriteString("\x1b[2J\x1b[H")
+	b.WriteString(ansiBold + "Suggestions (j/k move, enter commit, a amend, e edit, r regenerate, q quit):" + ansiReset + "\n")
+	for i, cs := range suggestions {
+		cursor := "  "
+		if i == idx {
+			cursor = ansiCyan + "> " + ansiReset
+		}
+		fmt.Fprintf(&b, "%s%d. %s\n", cursor, i+1, commitHeader(cs))
+	}
+	b.WriteString(ansiBold + "\nDiff:" + ansiReset + "\n")
+	b.WriteString(diffView)
+	fmt.Fprint(os.Stdout, b.String())
+}
+
+// colorizeDiff adds simple ANSI coloring to added/removed/hunk lines,
+// standing in for a syntax highlighter the repo has no dependency on,
+// and truncates long diffs to maxDiffPreviewLines.
+func colorizeDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	truncated := false
+	if len(lines) > maxDiffPreviewLines {
+		lines = lines[:maxDiffPreviewLines]
+		truncated = true
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
+			b.WriteString(line)
+		case strings.HasPrefix(line, "+"):
+			b.WriteString(ansiGreen + line + ansiReset)
+		case strings.HasPrefix(line, "-"):
+			b.WriteString(ansiRed + line + ansiReset)
+		case strings.HasPrefix(line, "@@"):
+			b.WriteString(ansiCyan + line + ansiReset)
+		default:
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	if truncated {
+		b.WriteString("... (diff truncated, see `git diff --staged` for the rest)\n")
+	}
+	return b.String()
+}
+
+// readKey reads a single byte from r, which must already be in raw
+// mode for this to return one keystroke at a time instead of a line.
+func readKey(r *os.File) (byte, error) {
+	var buf [1]byte
+	if _, err := r.Read(buf[:]); err != nil {
+		return 0, err
+	}
+	return buf[0], nil
+}
+
+// editMessage temporarily restores the terminal's normal mode, opens
+// message in $EDITOR (falling back to vi), and returns the edited
+// contents once the editor exits.
+func editMessage(mode *rawMode, fd int, message string) (string, error) {
+	if err := mode.restore(fd); err != nil {
+		return "", err
+	}
+	defer func() {
+		if _, err := enableRawMode(fd); err != nil {
+			fmt.Fprintln(os.Stderr, "warning: failed to re-enable raw mode:", err)
+		}
+	}()
+
+	tmp, err := os.CreateTemp("", "gocommit-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.WriteString(message); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("close temp file: %w", err)
+	}
+
+	editor := envOr("EDITOR", "vi")
+	cmd := exec.Command(editor, tmp.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("run editor: %w", err)
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", fmt.Errorf("read edited message: %w", err)
+	}
+	return strings.TrimRight(string(edited), "\n"), nil
+}